@@ -0,0 +1,23 @@
+// Command manager is the titanium controller-manager binary. It serves the
+// per-cluster Prometheus metrics on /metrics; it starts no controllers and
+// no cluster manager.
+package main
+
+import (
+	"flag"
+	"net/http"
+
+	"github.com/golang/glog"
+
+	"github.com/presslabs/titanium/pkg/metrics"
+)
+
+func main() {
+	metricsAddr := flag.String("metrics-addr", ":8080", "the address the metrics endpoint binds to")
+	flag.Parse()
+
+	http.Handle("/metrics", metrics.Handler())
+
+	glog.Infof("serving metrics on %s/metrics", *metricsAddr)
+	glog.Fatal(http.ListenAndServe(*metricsAddr, nil))
+}
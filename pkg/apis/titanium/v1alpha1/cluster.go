@@ -2,28 +2,99 @@ package v1alpha1
 
 import (
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/golang/glog"
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 
+	"github.com/presslabs/titanium/pkg/metrics"
 	"github.com/presslabs/titanium/pkg/util/options"
 	orc "github.com/presslabs/titanium/pkg/util/orchestrator"
 )
 
 const (
-	innodbBufferSizePercent = 80
+	// bufferPoolPercentConservative/Balanced/Aggressive are the
+	// innodb-buffer-pool-size shares of requested memory for each
+	// MysqlConfTuning level.
+	bufferPoolPercentConservative = 50
+	bufferPoolPercentBalanced     = 80
+	bufferPoolPercentAggressive   = 90
+
+	// innodbLogFileSizePercent is innodb-log-file-size as a share of the
+	// buffer pool, capped at maxInnodbLogFileSize.
+	innodbLogFileSizePercent = 25
+	maxInnodbLogFileSize     = 2 << 30 // 2G
+
+	// maxInnodbBufferPoolInstances caps the number of buffer pool
+	// instances even on very large nodes, since too many instances hurts
+	// small workloads more than it helps.
+	maxInnodbBufferPoolInstances = 8
+
+	// max_connections scales linearly with the CPU request, with a floor
+	// so small clusters still accept a reasonable number of connections.
+	maxConnectionsPerCPU = 100
+	minMaxConnections    = 100
+
+	// defaultStartupDelaySeconds is how long the controller waits after
+	// restarting a pod before considering it healthy, giving InnoDB crash
+	// recovery on large buffer pools time to finish.
+	defaultStartupDelaySeconds = 60
+
+	// defaultMaxSlaveLatencySeconds mirrors the threshold that was
+	// previously hard-coded in GetHealtySlaveHost.
+	defaultMaxSlaveLatencySeconds = 5
+
+	// defaultJuiceFSMountPath is where the backup sidecar mounts the
+	// JuiceFS filesystem when Spec.BackupStorage.JuiceFS.MountPath isn't set.
+	defaultJuiceFSMountPath = "/var/lib/mysql-juicefs"
 )
 
 var (
 	opt *options.Options
+
+	clusterCache ClusterCache
 )
 
 func init() {
 	opt = options.GetOptions()
 }
 
+// ClusterCache is the read side a cached topology provider must satisfy
+// to back GetMasterHost/GetHealtySlaveHost. pkg/clustering.ClusterManager
+// implements it structurally, without this package importing pkg/clustering
+// (which itself imports this package for *MysqlCluster) — that would be a
+// cycle.
+type ClusterCache interface {
+	GetMasterHost(name types.NamespacedName) string
+	GetHealtySlaveHost(name types.NamespacedName) string
+}
+
+// SetClusterCache registers the cache-backed topology provider that
+// GetMasterHost/GetHealtySlaveHost prefer over their own synchronous
+// orchestrator call, e.g. a pkg/clustering.ClusterManager, so a single
+// poller per cluster serves every caller instead of each call site hitting
+// orchestrator on its own. Until one is registered (or after a nil one is),
+// both use their synchronous orchestrator lookup.
+func SetClusterCache(c ClusterCache) {
+	clusterCache = c
+}
+
+// RegisterMetrics creates this cluster's Prometheus series. It should be
+// called once the MysqlCluster is created.
+func (c *MysqlCluster) RegisterMetrics() {
+	metrics.Register(c.Namespace, c.Name)
+}
+
+// UnregisterMetrics removes this cluster's Prometheus series so they
+// don't linger as stale after the MysqlCluster is deleted.
+func (c *MysqlCluster) UnregisterMetrics() {
+	metrics.Unregister(c.Namespace, c.Name)
+}
+
 // AsOwnerReference returns the MysqlCluster owner references.
 func (c *MysqlCluster) AsOwnerReference() metav1.OwnerReference {
 	trueVar := true
@@ -47,24 +118,117 @@ func (c *ClusterSpec) UpdateDefaults(opt *options.Options) error {
 		c.MysqlVersion = opt.MysqlImageTag
 	}
 
+	if c.StartupDelaySeconds == 0 {
+		c.StartupDelaySeconds = defaultStartupDelaySeconds
+	}
+
+	if c.MaxSlaveLatencySeconds == 0 {
+		c.MaxSlaveLatencySeconds = defaultMaxSlaveLatencySeconds
+	}
+
 	if err := c.PodSpec.UpdateDefaults(opt); err != nil {
 		return err
 	}
 
-	// set innodb-buffer-pool-size as 80% of requested memory
+	if err := c.BackupStorage.UpdateDefaults(); err != nil {
+		return err
+	}
+
+	if err := c.updateMysqlConfDefaults(); err != nil {
+		return err
+	}
+
+	return c.VolumeSpec.UpdateDefaults()
+}
+
+// bufferPoolPercent returns the share of requested memory given to
+// innodb-buffer-pool-size, controlled by MysqlConfTuning. An empty
+// MysqlConfTuning defaults to "balanced"; any other unrecognized value is
+// rejected rather than silently falling back, same as an unrecognized
+// BackupStorage configuration.
+func (c *ClusterSpec) bufferPoolPercent() (int64, error) {
+	switch c.MysqlConfTuning {
+	case "", MysqlConfTuningBalanced:
+		return bufferPoolPercentBalanced, nil
+	case MysqlConfTuningConservative:
+		return bufferPoolPercentConservative, nil
+	case MysqlConfTuningAggressive:
+		return bufferPoolPercentAggressive, nil
+	default:
+		return 0, fmt.Errorf("invalid mysqlConfTuning %q: must be one of %q, %q, %q",
+			c.MysqlConfTuning, MysqlConfTuningConservative, MysqlConfTuningBalanced, MysqlConfTuningAggressive)
+	}
+}
+
+// updateMysqlConfDefaults derives innodb-buffer-pool-size and a handful of
+// buffers proportional to it from the pod's resource requests, leaving any
+// value the user already set untouched.
+func (c *ClusterSpec) updateMysqlConfDefaults() error {
+	mem := c.PodSpec.Resources.Requests.Memory()
+	if mem == nil {
+		return nil
+	}
+
+	if len(c.MysqlConf) == 0 {
+		c.MysqlConf = make(MysqlConf)
+	}
+
+	percent, err := c.bufferPoolPercent()
+	if err != nil {
+		return err
+	}
+
+	bufferPoolSize := (percent * mem.Value()) / 100
+
 	if _, ok := c.MysqlConf["innodb-buffer-pool-size"]; !ok {
-		if mem := c.PodSpec.Resources.Requests.Memory(); mem != nil {
-			val := (innodbBufferSizePercent * mem.Value()) / 100 // val is 80% of requested memory
-			res := resource.NewQuantity(val, resource.DecimalSI)
-			if len(c.MysqlConf) == 0 {
-				c.MysqlConf = make(MysqlConf)
+		c.MysqlConf["innodb-buffer-pool-size"] = humanReadableQuantity(bufferPoolSize)
+	}
+
+	if _, ok := c.MysqlConf["innodb-log-file-size"]; !ok {
+		logFileSize := bufferPoolSize * innodbLogFileSizePercent / 100
+		if logFileSize > maxInnodbLogFileSize {
+			logFileSize = maxInnodbLogFileSize
+		}
+		c.MysqlConf["innodb-log-file-size"] = humanReadableQuantity(logFileSize)
+	}
+
+	if _, ok := c.MysqlConf["innodb-buffer-pool-instances"]; !ok {
+		instances := bufferPoolSize / (1 << 30) // one instance per GiB
+		if instances < 1 {
+			instances = 1
+		}
+		if instances > maxInnodbBufferPoolInstances {
+			instances = maxInnodbBufferPoolInstances
+		}
+		c.MysqlConf["innodb-buffer-pool-instances"] = strconv.FormatInt(instances, 10)
+	}
+
+	if _, ok := c.MysqlConf["max_connections"]; !ok {
+		if cpu := c.PodSpec.Resources.Requests.Cpu(); cpu != nil {
+			conns := cpu.MilliValue() * maxConnectionsPerCPU / 1000
+			if conns < minMaxConnections {
+				conns = minMaxConnections
 			}
-			// TODO: make it human readable
-			c.MysqlConf["innodb-buffer-pool-size"] = res.String()
+			c.MysqlConf["max_connections"] = strconv.FormatInt(conns, 10)
 		}
 	}
 
-	return c.VolumeSpec.UpdateDefaults()
+	return nil
+}
+
+// humanReadableQuantity formats a byte count in IEC form (e.g. 768Mi,
+// 4Gi) instead of plain decimal bytes. Counts of 1Mi or more are rounded
+// down to a whole number of Mi, and counts between 1Ki and 1Mi to a whole
+// number of Ki, since a count that isn't a multiple of 1Ki would otherwise
+// print as plain bytes. Counts below 1Ki print as plain bytes.
+func humanReadableQuantity(val int64) string {
+	switch {
+	case val >= 1<<20:
+		val &^= 1<<20 - 1
+	case val >= 1<<10:
+		val &^= 1<<10 - 1
+	}
+	return resource.NewQuantity(val, resource.BinarySI).String()
 }
 
 // GetTitaniumImage return titanium image from options
@@ -88,6 +252,76 @@ func (c *ClusterSpec) GetOrcTopologySecret() string {
 	return opt.OrchestratorTopologySecretName
 }
 
+// StartupDelay is StartupDelaySeconds as a time.Duration, for a
+// pod-restarting reconciler to wait on before considering a
+// just-restarted pod healthy.
+func (c *ClusterSpec) StartupDelay() time.Duration {
+	return time.Duration(c.StartupDelaySeconds) * time.Second
+}
+
+// GetBackupCredentialsSecret returns the name of the secret that contains
+// the credentials for whichever backup storage backend is configured.
+func (c *ClusterSpec) GetBackupCredentialsSecret() string {
+	if c.BackupStorage == nil {
+		return ""
+	}
+
+	switch {
+	case c.BackupStorage.S3 != nil:
+		return c.BackupStorage.S3.CredentialsSecretName
+	case c.BackupStorage.GCS != nil:
+		return c.BackupStorage.GCS.CredentialsSecretName
+	case c.BackupStorage.AzureBlob != nil:
+		return c.BackupStorage.AzureBlob.CredentialsSecretName
+	case c.BackupStorage.JuiceFS != nil:
+		return c.BackupStorage.JuiceFS.CredentialsSecretName
+	}
+
+	return ""
+}
+
+// BackupCredentialsEnvVar returns the env var the backup sidecar uses to
+// source credentials for whichever storage backend is configured, or nil
+// if backups aren't configured. It's consumed by the BackupCronJob
+// builder when assembling the sidecar container.
+func (c *ClusterSpec) BackupCredentialsEnvVar() *apiv1.EnvVar {
+	secret := c.GetBackupCredentialsSecret()
+	if len(secret) == 0 {
+		return nil
+	}
+
+	return &apiv1.EnvVar{
+		Name: "BACKUP_CREDENTIALS",
+		ValueFrom: &apiv1.EnvVarSource{
+			SecretKeyRef: &apiv1.SecretKeySelector{
+				LocalObjectReference: apiv1.LocalObjectReference{Name: secret},
+				Key:                  "credentials",
+			},
+		},
+	}
+}
+
+// FormatCmd returns the command the backup sidecar runs, once, to
+// initialize the JuiceFS metadata engine for this filesystem before the
+// first MountCmd.
+func (j *JuiceFSBackupStorage) FormatCmd(metaURL string) []string {
+	return []string{"juicefs", "format", metaURL, j.Bucket}
+}
+
+// MountCmd returns the command the backup sidecar runs to mount the
+// JuiceFS filesystem at MountPath before xtrabackup writes its output
+// there.
+func (j *JuiceFSBackupStorage) MountCmd(metaURL string) []string {
+	return []string{"juicefs", "mount", "-d", metaURL, j.MountPath}
+}
+
+// UnmountCmd returns the command the backup sidecar runs to cleanly
+// unmount the filesystem once xtrabackup completes, so the backup is
+// flushed to object storage before the job exits.
+func (j *JuiceFSBackupStorage) UnmountCmd() []string {
+	return []string{"umount", j.MountPath}
+}
+
 // GetMysqlImage returns mysql image, composed from oprions and  Spec.MysqlVersion
 func (c *ClusterSpec) GetMysqlImage() string {
 	return opt.MysqlImage + ":" + c.MysqlVersion
@@ -114,9 +348,57 @@ func (ps *PodSpec) UpdateDefaults(opt *options.Options) error {
 			},
 		}
 	}
+
+	return ps.validateExtras()
+}
+
+// validateExtras rejects duplicate names in ExtraEnv/ExtraVolumes before
+// they ever reach the statefulset builder, since a duplicate env var or
+// volume name silently shadows one set elsewhere instead of failing loudly.
+func (ps *PodSpec) validateExtras() error {
+	envNames := make(map[string]bool, len(ps.ExtraEnv))
+	for _, e := range ps.ExtraEnv {
+		if envNames[e.Name] {
+			return fmt.Errorf("duplicate extraEnv entry %q", e.Name)
+		}
+		envNames[e.Name] = true
+	}
+
+	volNames := make(map[string]bool, len(ps.ExtraVolumes))
+	for _, v := range ps.ExtraVolumes {
+		if volNames[v.Name] {
+			return fmt.Errorf("duplicate extraVolume entry %q", v.Name)
+		}
+		volNames[v.Name] = true
+	}
+
 	return nil
 }
 
+// AppendExtraEnv appends ExtraEnv to a container's env, letting operators
+// inject things like vault-agent configuration or cloud-provider
+// credentials without forking the operator. It's called by the
+// statefulset builder when assembling the mysql and sidecar containers.
+func (ps *PodSpec) AppendExtraEnv(env []apiv1.EnvVar) []apiv1.EnvVar {
+	return append(env, ps.ExtraEnv...)
+}
+
+// AppendExtraEnvFrom appends ExtraEnvFrom to a container's envFrom.
+func (ps *PodSpec) AppendExtraEnvFrom(envFrom []apiv1.EnvFromSource) []apiv1.EnvFromSource {
+	return append(envFrom, ps.ExtraEnvFrom...)
+}
+
+// AppendExtraVolumes appends ExtraVolumes to the pod's volumes.
+func (ps *PodSpec) AppendExtraVolumes(volumes []apiv1.Volume) []apiv1.Volume {
+	return append(volumes, ps.ExtraVolumes...)
+}
+
+// AppendExtraVolumeMounts appends ExtraVolumeMounts to a container's
+// volumeMounts.
+func (ps *PodSpec) AppendExtraVolumeMounts(mounts []apiv1.VolumeMount) []apiv1.VolumeMount {
+	return append(mounts, ps.ExtraVolumeMounts...)
+}
+
 // UpdateDefaults for VolumeSpec
 func (vs *VolumeSpec) UpdateDefaults() error {
 	if len(vs.AccessModes) == 0 {
@@ -136,6 +418,68 @@ func (vs *VolumeSpec) UpdateDefaults() error {
 	return nil
 }
 
+// UpdateDefaults defaults the JuiceFS mount path and validates that
+// exactly one backend is configured, if any. A nil BackupStorage (backups
+// disabled) is left untouched.
+func (bs *BackupStorage) UpdateDefaults() error {
+	if bs == nil {
+		return nil
+	}
+
+	backends := 0
+	if bs.S3 != nil {
+		backends++
+	}
+	if bs.GCS != nil {
+		backends++
+	}
+	if bs.AzureBlob != nil {
+		backends++
+	}
+	if bs.JuiceFS != nil {
+		backends++
+		if len(bs.JuiceFS.MountPath) == 0 {
+			bs.JuiceFS.MountPath = defaultJuiceFSMountPath
+		}
+	}
+
+	if backends != 1 {
+		return fmt.Errorf("exactly one backupStorage backend must be configured, got %d", backends)
+	}
+
+	return nil
+}
+
+// SetClusterCondition updates the condition of the given type, adding it
+// if it doesn't exist yet. It's used by the cluster manager to record the
+// availability/health observed from orchestrator onto Status.
+func (c *MysqlCluster) SetClusterCondition(condType ClusterConditionType, status apiv1.ConditionStatus, reason, message string) {
+	now := metav1.Now()
+
+	for i := range c.Status.Conditions {
+		cond := &c.Status.Conditions[i]
+		if cond.Type != condType {
+			continue
+		}
+
+		if cond.Status != status {
+			cond.Status = status
+			cond.LastTransitionTime = now
+		}
+		cond.Reason = reason
+		cond.Message = message
+		return
+	}
+
+	c.Status.Conditions = append(c.Status.Conditions, ClusterCondition{
+		Type:               condType,
+		Status:             status,
+		LastTransitionTime: now,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
 // ResourceName is the type for aliasing resources that will be created.
 type ResourceName string
 
@@ -160,20 +504,32 @@ func getNameForResource(name ResourceName, clusterName string) string {
 	return fmt.Sprintf("%s-mysql", clusterName)
 }
 
+// GetHealtySlaveHost returns a healthy replica's hostname, preferring a
+// registered ClusterCache over a synchronous orchestrator lookup.
 func (c *MysqlCluster) GetHealtySlaveHost() string {
+	if clusterCache != nil {
+		if host := clusterCache.GetHealtySlaveHost(c.namespacedName()); len(host) != 0 {
+			return host
+		}
+	}
+
 	host := fmt.Sprintf("%s-%d.%s", c.GetNameForResource(StatefulSet), c.Status.ReadyNodes-1,
 		c.GetNameForResource(HeadlessSVC))
 
 	if len(c.Spec.GetOrcUri()) != 0 {
 		glog.V(2).Info("[GetHealtySlaveHost]: Use orchestrator to get slave host.")
 		client := orc.NewFromUri(c.Spec.GetOrcUri())
+
+		c.MaybeSwitchoverForUpgrade(client)
+
 		replicas, err := client.ClusterOSCReplicas(c.Name)
 		if err != nil {
 			glog.Errorf("[GetHealtySlaveHost] orc failed with: %s", err)
 			return host
 		}
 		for _, r := range replicas {
-			if r.SecondsBehindMaster.Valid && r.SecondsBehindMaster.Int64 <= 5 {
+			c.RecordObservedVersion(r.Key.Hostname, r.Version)
+			if r.SecondsBehindMaster.Valid && r.SecondsBehindMaster.Int64 <= int64(c.Spec.MaxSlaveLatencySeconds) {
 				glog.V(2).Infof("[GetHealtySlaveHost]: Using orc we choses: %s", r.Key.Hostname)
 				host = r.Key.Hostname
 			}
@@ -184,13 +540,25 @@ func (c *MysqlCluster) GetHealtySlaveHost() string {
 	return host
 }
 
+// GetMasterHost returns the current master's hostname, preferring a
+// registered ClusterCache over a synchronous orchestrator lookup.
 func (c *MysqlCluster) GetMasterHost() string {
+	if clusterCache != nil {
+		if host := clusterCache.GetMasterHost(c.namespacedName()); len(host) != 0 {
+			return host
+		}
+	}
+
 	masterHost := c.GetPodHostName(0)
 	// connect to orc and get the master host of the cluster.
 	if len(c.Spec.GetOrcUri()) != 0 {
 		client := orc.NewFromUri(c.Spec.GetOrcUri())
+
+		c.MaybeSwitchoverForUpgrade(client)
+
 		if inst, err := client.Master(c.Name); err == nil {
 			masterHost = inst.Key.Hostname
+			c.RecordObservedVersion(inst.Key.Hostname, inst.Version)
 		} else {
 			glog.Warning(
 				"[GetMasterHost]: Failed to connect to orcheatratoro: %s, failback to default",
@@ -202,7 +570,127 @@ func (c *MysqlCluster) GetMasterHost() string {
 	return masterHost
 }
 
+// MaybeSwitchoverForUpgrade triggers the upgrade switchover when it's due.
+// Called from both the synchronous orchestrator path (GetMasterHost,
+// GetHealtySlaveHost) and the cache-backed path (clusterWorker.poll), since
+// whichever is active may be the first to observe that every replica has
+// rolled to the new MySQL version. Guarded by
+// ClusterConditionUpgradeSwitchover so a takeover already triggered for
+// this MysqlVersion isn't re-issued against an already-promoted candidate
+// on every subsequent call.
+func (c *MysqlCluster) MaybeSwitchoverForUpgrade(client orc.Interface) {
+	if !c.upgradeAwaitingMasterSwitchover() {
+		return
+	}
+
+	if cond := c.clusterCondition(ClusterConditionUpgradeSwitchover); cond != nil &&
+		cond.Status == apiv1.ConditionTrue && cond.Message == c.Spec.MysqlVersion {
+		return
+	}
+
+	if err := c.switchoverForUpgrade(client); err != nil {
+		glog.Errorf("[MaybeSwitchoverForUpgrade]: upgrade switchover failed: %s", err)
+		return
+	}
+
+	c.SetClusterCondition(ClusterConditionUpgradeSwitchover, apiv1.ConditionTrue,
+		"GracefulMasterTakeover", c.Spec.MysqlVersion)
+}
+
+// clusterCondition returns the condition of the given type, or nil if it
+// hasn't been set yet.
+func (c *MysqlCluster) clusterCondition(condType ClusterConditionType) *ClusterCondition {
+	for i := range c.Status.Conditions {
+		if c.Status.Conditions[i].Type == condType {
+			return &c.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// RecordObservedVersion records, onto Status.Nodes, the MySQL version
+// orchestrator reports for the given pod hostname. GetMasterHost,
+// GetHealtySlaveHost and clusterWorker.poll call this for every instance
+// they observe, which is what actually drives the per-pod version
+// tracking that upgradeAwaitingMasterSwitchover reads: as the controller
+// reconciles and polls orchestrator pod-by-pod during a rolling upgrade,
+// each newly-upgraded pod's version lands here.
+func (c *MysqlCluster) RecordObservedVersion(hostname, version string) {
+	if len(hostname) == 0 || len(version) == 0 {
+		return
+	}
+
+	for i := range c.Status.Nodes {
+		if c.Status.Nodes[i].Name == hostname {
+			c.Status.Nodes[i].Version = version
+			return
+		}
+	}
+
+	c.Status.Nodes = append(c.Status.Nodes, NodeStatus{Name: hostname, Version: version})
+}
+
+// upgradeAwaitingMasterSwitchover returns true when every pod but the
+// master (ordinal 0) has already been rolled to Spec.MysqlVersion. The
+// rolling upgrade proceeds from the highest ordinal down, so once it
+// reaches this point pod-0 can't be upgraded in place without first
+// moving mastership off of it. It requires Status.Nodes to cover every
+// replica (ordinals 1..ReadyNodes-1); an incomplete view (e.g. early in
+// the rollout, before every pod has been observed) is treated as "not
+// ready to switch over" rather than a false positive.
+func (c *MysqlCluster) upgradeAwaitingMasterSwitchover() bool {
+	if c.Status.ReadyNodes < 2 {
+		return false
+	}
+
+	versions := make(map[string]string, len(c.Status.Nodes))
+	for _, n := range c.Status.Nodes {
+		versions[n.Name] = n.Version
+	}
+
+	for ord := 1; ord < c.Status.ReadyNodes; ord++ {
+		if versions[c.GetPodHostName(ord)] != c.Spec.MysqlVersion {
+			return false
+		}
+	}
+
+	return versions[c.GetPodHostName(0)] != c.Spec.MysqlVersion
+}
+
+// switchoverForUpgrade promotes the lowest-ordinal, already-upgraded
+// replica (pod-1) to master once it has caught up, so that pod-0 can be
+// restarted on the new MySQL version.
+func (c *MysqlCluster) switchoverForUpgrade(client orc.Interface) error {
+	candidate := c.GetPodHostName(1)
+
+	replicas, err := client.ClusterOSCReplicas(c.Name)
+	if err != nil {
+		return fmt.Errorf("failed to list replicas: %s", err)
+	}
+
+	for _, r := range replicas {
+		if r.Key.Hostname != candidate {
+			continue
+		}
+
+		if !r.SecondsBehindMaster.Valid || r.SecondsBehindMaster.Int64 > int64(c.Spec.MaxSlaveLatencySeconds) {
+			return fmt.Errorf("candidate master %s hasn't caught up yet", candidate)
+		}
+
+		glog.Infof("[switchoverForUpgrade]: promoting %s to master for version upgrade", candidate)
+		return client.GracefulMasterTakeover(c.Name, candidate)
+	}
+
+	return fmt.Errorf("candidate master %s not found in orchestrator topology", candidate)
+}
+
 func (c *MysqlCluster) GetPodHostName(p int) string {
 	pod := fmt.Sprintf("%s-%d", c.GetNameForResource(StatefulSet), p)
 	return fmt.Sprintf("%s.%s", pod, c.GetNameForResource(HeadlessSVC))
-}
\ No newline at end of file
+}
+
+// namespacedName is the key a registered ClusterCache tracks this cluster
+// under.
+func (c *MysqlCluster) namespacedName() types.NamespacedName {
+	return types.NamespacedName{Namespace: c.Namespace, Name: c.Name}
+}
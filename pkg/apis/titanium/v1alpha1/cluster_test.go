@@ -0,0 +1,374 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// fakeClusterCache is a ClusterCache that always returns the same hosts,
+// regardless of which cluster is asked for.
+type fakeClusterCache struct {
+	master string
+	slave  string
+}
+
+func (f fakeClusterCache) GetMasterHost(types.NamespacedName) string      { return f.master }
+func (f fakeClusterCache) GetHealtySlaveHost(types.NamespacedName) string { return f.slave }
+
+func TestGetMasterAndSlaveHostPreferRegisteredClusterCache(t *testing.T) {
+	defer SetClusterCache(nil)
+	SetClusterCache(fakeClusterCache{master: "cached-master", slave: "cached-slave"})
+
+	c := &MysqlCluster{}
+	if got := c.GetMasterHost(); got != "cached-master" {
+		t.Errorf("GetMasterHost() = %q, want the cached host", got)
+	}
+	if got := c.GetHealtySlaveHost(); got != "cached-slave" {
+		t.Errorf("GetHealtySlaveHost() = %q, want the cached host", got)
+	}
+}
+
+func newUpgradeTestCluster(readyNodes int, mysqlVersion string, nodes []NodeStatus) *MysqlCluster {
+	return &MysqlCluster{
+		Spec: ClusterSpec{
+			MysqlVersion: mysqlVersion,
+		},
+		Status: ClusterStatus{
+			ReadyNodes: readyNodes,
+			Nodes:      nodes,
+		},
+	}
+}
+
+func TestUpgradeAwaitingMasterSwitchover(t *testing.T) {
+	c := newUpgradeTestCluster(0, "8.0", nil)
+
+	cases := []struct {
+		name     string
+		cluster  *MysqlCluster
+		expected bool
+	}{
+		{
+			name:     "no status reported yet",
+			cluster:  newUpgradeTestCluster(3, "8.0", nil),
+			expected: false,
+		},
+		{
+			name: "replicas upgraded, master pending switchover",
+			cluster: newUpgradeTestCluster(3, "8.0", []NodeStatus{
+				{Name: c.GetPodHostName(0), Version: "5.7"},
+				{Name: c.GetPodHostName(1), Version: "8.0"},
+				{Name: c.GetPodHostName(2), Version: "8.0"},
+			}),
+			expected: true,
+		},
+		{
+			name: "still rolling out, one replica not upgraded yet",
+			cluster: newUpgradeTestCluster(3, "8.0", []NodeStatus{
+				{Name: c.GetPodHostName(0), Version: "5.7"},
+				{Name: c.GetPodHostName(1), Version: "5.7"},
+				{Name: c.GetPodHostName(2), Version: "8.0"},
+			}),
+			expected: false,
+		},
+		{
+			name: "already fully upgraded, no switchover needed",
+			cluster: newUpgradeTestCluster(3, "8.0", []NodeStatus{
+				{Name: c.GetPodHostName(0), Version: "8.0"},
+				{Name: c.GetPodHostName(1), Version: "8.0"},
+				{Name: c.GetPodHostName(2), Version: "8.0"},
+			}),
+			expected: false,
+		},
+		{
+			name: "incomplete view of a replica's status is not a false positive",
+			cluster: newUpgradeTestCluster(3, "8.0", []NodeStatus{
+				{Name: c.GetPodHostName(0), Version: "5.7"},
+				{Name: c.GetPodHostName(1), Version: "8.0"},
+			}),
+			expected: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.cluster.upgradeAwaitingMasterSwitchover(); got != tc.expected {
+				t.Errorf("upgradeAwaitingMasterSwitchover() = %v, want %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestRecordObservedVersion(t *testing.T) {
+	c := newUpgradeTestCluster(2, "8.0", nil)
+
+	c.RecordObservedVersion(c.GetPodHostName(1), "8.0")
+	if len(c.Status.Nodes) != 1 || c.Status.Nodes[0].Version != "8.0" {
+		t.Fatalf("expected a new node status to be recorded, got %+v", c.Status.Nodes)
+	}
+
+	c.RecordObservedVersion(c.GetPodHostName(1), "8.0.1")
+	if len(c.Status.Nodes) != 1 || c.Status.Nodes[0].Version != "8.0.1" {
+		t.Fatalf("expected the existing node status to be updated in place, got %+v", c.Status.Nodes)
+	}
+
+	c.RecordObservedVersion(c.GetPodHostName(0), "5.7")
+	if len(c.Status.Nodes) != 2 {
+		t.Fatalf("expected a second node status to be recorded, got %+v", c.Status.Nodes)
+	}
+}
+
+func TestMaybeSwitchoverForUpgradeIsIdempotent(t *testing.T) {
+	names := &MysqlCluster{}
+	c := newUpgradeTestCluster(2, "8.0", []NodeStatus{
+		{Name: names.GetPodHostName(0), Version: "5.7"},
+		{Name: names.GetPodHostName(1), Version: "8.0"},
+	})
+	c.SetClusterCondition(ClusterConditionUpgradeSwitchover, apiv1.ConditionTrue, "GracefulMasterTakeover", "8.0")
+
+	// A nil orc.Interface would panic if switchoverForUpgrade were called
+	// again; MaybeSwitchoverForUpgrade must short-circuit on the condition
+	// it already set for this MysqlVersion instead of reaching it.
+	c.MaybeSwitchoverForUpgrade(nil)
+}
+
+func TestMaybeSwitchoverForUpgradeRetriggersOnNextUpgrade(t *testing.T) {
+	names := &MysqlCluster{}
+	c := newUpgradeTestCluster(2, "8.0.1", []NodeStatus{
+		{Name: names.GetPodHostName(0), Version: "8.0"},
+		{Name: names.GetPodHostName(1), Version: "8.0.1"},
+	})
+	c.SetClusterCondition(ClusterConditionUpgradeSwitchover, apiv1.ConditionTrue, "GracefulMasterTakeover", "8.0")
+
+	if cond := c.clusterCondition(ClusterConditionUpgradeSwitchover); cond == nil || cond.Message == c.Spec.MysqlVersion {
+		t.Fatalf("test setup invalid: condition must be stale for this case, got %+v", cond)
+	}
+
+	// The condition was left by the 8.0 upgrade, so MaybeSwitchoverForUpgrade
+	// must go on to switchoverForUpgrade, which panics on the nil client.
+	defer func() {
+		if recover() == nil {
+			t.Error("expected MaybeSwitchoverForUpgrade to trigger a switchover despite a condition left by an earlier version")
+		}
+	}()
+	c.MaybeSwitchoverForUpgrade(nil)
+}
+
+func TestBufferPoolPercent(t *testing.T) {
+	cases := []struct {
+		tuning  MysqlConfTuning
+		percent int64
+		wantErr bool
+	}{
+		{tuning: "", percent: bufferPoolPercentBalanced},
+		{tuning: MysqlConfTuningBalanced, percent: bufferPoolPercentBalanced},
+		{tuning: MysqlConfTuningConservative, percent: bufferPoolPercentConservative},
+		{tuning: MysqlConfTuningAggressive, percent: bufferPoolPercentAggressive},
+		{tuning: "agressive", wantErr: true},
+		{tuning: "balanced ", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		c := &ClusterSpec{MysqlConfTuning: tc.tuning}
+		got, err := c.bufferPoolPercent()
+
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("bufferPoolPercent() for tuning %q: expected an error, got percent=%d", tc.tuning, got)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("bufferPoolPercent() for tuning %q: unexpected error: %s", tc.tuning, err)
+		}
+		if got != tc.percent {
+			t.Errorf("bufferPoolPercent() for tuning %q = %d, want %d", tc.tuning, got, tc.percent)
+		}
+	}
+}
+
+func TestUpdateMysqlConfDefaults(t *testing.T) {
+	c := &ClusterSpec{
+		PodSpec: PodSpec{
+			Resources: apiv1.ResourceRequirements{
+				Requests: apiv1.ResourceList{
+					apiv1.ResourceCPU:    resource.MustParse("2"),
+					apiv1.ResourceMemory: resource.MustParse("4Gi"),
+				},
+			},
+		},
+	}
+
+	if err := c.updateMysqlConfDefaults(); err != nil {
+		t.Fatalf("updateMysqlConfDefaults() returned an error: %s", err)
+	}
+
+	// balanced profile: 80% of 4Gi = 3.2Gi, rounded down to 3276Mi for IEC form.
+	bufferPoolSize, err := resource.ParseQuantity(c.MysqlConf["innodb-buffer-pool-size"])
+	if err != nil {
+		t.Fatalf("innodb-buffer-pool-size %q doesn't parse as a quantity: %s", c.MysqlConf["innodb-buffer-pool-size"], err)
+	}
+	wantBufferPoolSize := int64(4*1024*1024*1024) * bufferPoolPercentBalanced / 100 &^ (1<<20 - 1)
+	if bufferPoolSize.Value() != wantBufferPoolSize {
+		t.Errorf("innodb-buffer-pool-size = %d bytes, want %d bytes", bufferPoolSize.Value(), wantBufferPoolSize)
+	}
+
+	logFileSize, err := resource.ParseQuantity(c.MysqlConf["innodb-log-file-size"])
+	if err != nil {
+		t.Fatalf("innodb-log-file-size doesn't parse as a quantity: %s", err)
+	}
+	wantLogFileSize := wantBufferPoolSize * innodbLogFileSizePercent / 100
+	if logFileSize.Value() != wantLogFileSize {
+		t.Errorf("innodb-log-file-size = %d bytes, want %d bytes", logFileSize.Value(), wantLogFileSize)
+	}
+
+	if got, want := c.MysqlConf["innodb-buffer-pool-instances"], "3"; got != want {
+		t.Errorf("innodb-buffer-pool-instances = %s, want %s (1 per GiB of a 3.2Gi pool)", got, want)
+	}
+
+	if got, want := c.MysqlConf["max_connections"], "200"; got != want {
+		t.Errorf("max_connections = %s, want %s (100 per CPU * 2 CPUs)", got, want)
+	}
+}
+
+func TestUpdateMysqlConfDefaultsCapsLogFileSize(t *testing.T) {
+	c := &ClusterSpec{
+		MysqlConfTuning: MysqlConfTuningAggressive,
+		PodSpec: PodSpec{
+			Resources: apiv1.ResourceRequirements{
+				Requests: apiv1.ResourceList{
+					apiv1.ResourceMemory: resource.MustParse("64Gi"),
+				},
+			},
+		},
+	}
+
+	if err := c.updateMysqlConfDefaults(); err != nil {
+		t.Fatalf("updateMysqlConfDefaults() returned an error: %s", err)
+	}
+
+	logFileSize, err := resource.ParseQuantity(c.MysqlConf["innodb-log-file-size"])
+	if err != nil {
+		t.Fatalf("innodb-log-file-size doesn't parse as a quantity: %s", err)
+	}
+	if logFileSize.Value() != maxInnodbLogFileSize {
+		t.Errorf("innodb-log-file-size = %d bytes, want the %d byte cap", logFileSize.Value(), maxInnodbLogFileSize)
+	}
+
+	if got, want := c.MysqlConf["innodb-buffer-pool-instances"], "8"; got != want {
+		t.Errorf("innodb-buffer-pool-instances = %s, want %s (capped)", got, want)
+	}
+}
+
+func TestUpdateMysqlConfDefaultsRejectsInvalidTuning(t *testing.T) {
+	c := &ClusterSpec{
+		MysqlConfTuning: "not-a-real-profile",
+		PodSpec: PodSpec{
+			Resources: apiv1.ResourceRequirements{
+				Requests: apiv1.ResourceList{
+					apiv1.ResourceMemory: resource.MustParse("4Gi"),
+				},
+			},
+		},
+	}
+
+	if err := c.updateMysqlConfDefaults(); err == nil {
+		t.Fatal("expected an error for an invalid MysqlConfTuning, got nil")
+	}
+}
+
+func TestPodSpecAppendExtras(t *testing.T) {
+	ps := &PodSpec{
+		ExtraEnv:          []apiv1.EnvVar{{Name: "VAULT_ADDR", Value: "https://vault:8200"}},
+		ExtraEnvFrom:      []apiv1.EnvFromSource{{SecretRef: &apiv1.SecretEnvSource{LocalObjectReference: apiv1.LocalObjectReference{Name: "cloud-creds"}}}},
+		ExtraVolumes:      []apiv1.Volume{{Name: "vault-agent"}},
+		ExtraVolumeMounts: []apiv1.VolumeMount{{Name: "vault-agent", MountPath: "/vault"}},
+	}
+
+	env := ps.AppendExtraEnv([]apiv1.EnvVar{{Name: "MYSQL_ROOT_PASSWORD"}})
+	if len(env) != 2 || env[1].Name != "VAULT_ADDR" {
+		t.Errorf("AppendExtraEnv() = %+v, want base entries followed by ExtraEnv", env)
+	}
+
+	envFrom := ps.AppendExtraEnvFrom(nil)
+	if len(envFrom) != 1 {
+		t.Errorf("AppendExtraEnvFrom() = %+v, want 1 entry", envFrom)
+	}
+
+	volumes := ps.AppendExtraVolumes([]apiv1.Volume{{Name: "data"}})
+	if len(volumes) != 2 || volumes[1].Name != "vault-agent" {
+		t.Errorf("AppendExtraVolumes() = %+v, want base entries followed by ExtraVolumes", volumes)
+	}
+
+	mounts := ps.AppendExtraVolumeMounts(nil)
+	if len(mounts) != 1 || mounts[0].Name != "vault-agent" {
+		t.Errorf("AppendExtraVolumeMounts() = %+v, want 1 entry", mounts)
+	}
+}
+
+func TestPodSpecValidateExtrasRejectsDuplicates(t *testing.T) {
+	dupEnv := &PodSpec{
+		ExtraEnv: []apiv1.EnvVar{{Name: "FOO", Value: "1"}, {Name: "FOO", Value: "2"}},
+	}
+	if err := dupEnv.validateExtras(); err == nil {
+		t.Error("expected an error for a duplicate extraEnv name, got nil")
+	}
+
+	dupVol := &PodSpec{
+		ExtraVolumes: []apiv1.Volume{{Name: "data"}, {Name: "data"}},
+	}
+	if err := dupVol.validateExtras(); err == nil {
+		t.Error("expected an error for a duplicate extraVolume name, got nil")
+	}
+
+	ok := &PodSpec{
+		ExtraEnv:     []apiv1.EnvVar{{Name: "FOO"}, {Name: "BAR"}},
+		ExtraVolumes: []apiv1.Volume{{Name: "a"}, {Name: "b"}},
+	}
+	if err := ok.validateExtras(); err != nil {
+		t.Errorf("validateExtras() returned an unexpected error: %s", err)
+	}
+}
+
+func TestBackupCredentialsEnvVar(t *testing.T) {
+	c := &ClusterSpec{}
+	if ev := c.BackupCredentialsEnvVar(); ev != nil {
+		t.Errorf("BackupCredentialsEnvVar() with no BackupStorage = %+v, want nil", ev)
+	}
+
+	c.BackupStorage = &BackupStorage{
+		JuiceFS: &JuiceFSBackupStorage{Bucket: "backups", CredentialsSecretName: "juicefs-creds"},
+	}
+
+	ev := c.BackupCredentialsEnvVar()
+	if ev == nil {
+		t.Fatal("BackupCredentialsEnvVar() = nil, want a SecretKeyRef env var")
+	}
+	if ev.ValueFrom == nil || ev.ValueFrom.SecretKeyRef == nil || ev.ValueFrom.SecretKeyRef.Name != "juicefs-creds" {
+		t.Errorf("BackupCredentialsEnvVar() = %+v, want SecretKeyRef to juicefs-creds", ev)
+	}
+}
+
+func TestJuiceFSBackupStorageCommands(t *testing.T) {
+	j := &JuiceFSBackupStorage{Bucket: "backups", MountPath: "/var/lib/mysql-juicefs"}
+	metaURL := "redis://juicefs-meta:6379/1"
+
+	format := j.FormatCmd(metaURL)
+	if len(format) == 0 || format[len(format)-1] != "backups" {
+		t.Errorf("FormatCmd() = %v, want it to reference the bucket", format)
+	}
+
+	mount := j.MountCmd(metaURL)
+	if len(mount) == 0 || mount[len(mount)-1] != j.MountPath {
+		t.Errorf("MountCmd() = %v, want it to mount at %s", mount, j.MountPath)
+	}
+
+	unmount := j.UnmountCmd()
+	if len(unmount) == 0 || unmount[len(unmount)-1] != j.MountPath {
+		t.Errorf("UnmountCmd() = %v, want it to unmount %s", unmount, j.MountPath)
+	}
+}
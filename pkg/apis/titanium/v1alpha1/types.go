@@ -0,0 +1,225 @@
+package v1alpha1
+
+import (
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MysqlClusterKind is the kind for the MysqlCluster CRD.
+const MysqlClusterKind = "MysqlCluster"
+
+// MysqlCluster is the Schema for the mysqlclusters API.
+type MysqlCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterSpec   `json:"spec,omitempty"`
+	Status ClusterStatus `json:"status,omitempty"`
+}
+
+// MysqlClusterList contains a list of MysqlCluster.
+type MysqlClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MysqlCluster `json:"items"`
+}
+
+// MysqlConf defines the mysql configuration entries that are rendered into
+// the my.cnf configuration file.
+type MysqlConf map[string]string
+
+// ClusterSpec defines the desired state of a MysqlCluster.
+type ClusterSpec struct {
+	// Replicas is the number of pods in the statefulset, including the master.
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// MysqlVersion represents the MySQL version that will be run. It's used
+	// to compose the image tag that will be used to create the pods.
+	MysqlVersion string `json:"mysqlVersion,omitempty"`
+
+	// StartupDelaySeconds is the amount of time a pod-restarting reconciler
+	// should wait after restarting a pod before considering it healthy
+	// again. Large InnoDB buffer pools can take minutes to recover after a
+	// crash or a restart, so rollouts that restart pods too eagerly can
+	// cascade into an outage. Consumed via ClusterSpec.StartupDelay; this
+	// package has no pod/clientset access of its own to perform the
+	// restart.
+	StartupDelaySeconds int32 `json:"startupDelaySeconds,omitempty"`
+
+	// MaxSlaveLatencySeconds is the maximum replication lag, in seconds, a
+	// replica may have and still be considered healthy enough to serve
+	// reads or be promoted to master.
+	MaxSlaveLatencySeconds int32 `json:"maxSlaveLatencySeconds,omitempty"`
+
+	// PodSpec is the spec that's used to customize the mysql and sidecar
+	// pods created for this cluster.
+	PodSpec PodSpec `json:"podSpec,omitempty"`
+
+	// VolumeSpec is the spec for the persistent volume claim used by the
+	// mysql pods.
+	VolumeSpec VolumeSpec `json:"volumeSpec,omitempty"`
+
+	// MysqlConf is a map of configuration entries that are passed to mysqld.
+	MysqlConf MysqlConf `json:"mysqlConf,omitempty"`
+
+	// BackupStorage configures where the BackupCronJob ships backups to.
+	// Exactly one of the fields below must be set.
+	BackupStorage *BackupStorage `json:"backupStorage,omitempty"`
+
+	// MysqlConfTuning controls how much of the pod's requested memory is
+	// given to innodb-buffer-pool-size and the buffers derived from it.
+	// Defaults to "balanced" when unset.
+	MysqlConfTuning MysqlConfTuning `json:"mysqlConfTuning,omitempty"`
+}
+
+// MysqlConfTuning selects a memory-tuning profile for the generated
+// my.cnf defaults.
+type MysqlConfTuning string
+
+const (
+	// MysqlConfTuningConservative leaves more memory headroom for other
+	// workloads sharing the node.
+	MysqlConfTuningConservative MysqlConfTuning = "conservative"
+	// MysqlConfTuningBalanced is the default profile.
+	MysqlConfTuningBalanced MysqlConfTuning = "balanced"
+	// MysqlConfTuningAggressive maximizes the buffer pool for
+	// dedicated/single-tenant nodes.
+	MysqlConfTuningAggressive MysqlConfTuning = "aggressive"
+)
+
+// BackupStorage configures the storage backend used by the backup
+// sidecar. Exactly one field must be set.
+type BackupStorage struct {
+	// S3 ships backups to an S3-compatible bucket using xbcloud.
+	S3 *S3BackupStorage `json:"s3,omitempty"`
+	// GCS ships backups to a Google Cloud Storage bucket using xbcloud.
+	GCS *GCSBackupStorage `json:"gcs,omitempty"`
+	// AzureBlob ships backups to an Azure Blob Storage container using xbcloud.
+	AzureBlob *AzureBlobBackupStorage `json:"azureBlob,omitempty"`
+	// JuiceFS mounts a JuiceFS filesystem backed by object storage and runs
+	// xtrabackup against it directly, giving incremental, deduplicated
+	// backups without xbcloud.
+	JuiceFS *JuiceFSBackupStorage `json:"juiceFS,omitempty"`
+}
+
+// S3BackupStorage configures backups shipped to an S3-compatible bucket.
+type S3BackupStorage struct {
+	Bucket string `json:"bucket"`
+	Region string `json:"region,omitempty"`
+	// CredentialsSecretName is a secret holding the access key ID and
+	// secret access key.
+	CredentialsSecretName string `json:"credentialsSecretName,omitempty"`
+}
+
+// GCSBackupStorage configures backups shipped to a Google Cloud Storage bucket.
+type GCSBackupStorage struct {
+	Bucket string `json:"bucket"`
+	// CredentialsSecretName is a secret holding the service account JSON key.
+	CredentialsSecretName string `json:"credentialsSecretName,omitempty"`
+}
+
+// AzureBlobBackupStorage configures backups shipped to an Azure Blob
+// Storage container.
+type AzureBlobBackupStorage struct {
+	Container string `json:"container"`
+	// CredentialsSecretName is a secret holding the storage account name
+	// and key.
+	CredentialsSecretName string `json:"credentialsSecretName,omitempty"`
+}
+
+// JuiceFSBackupStorage configures a JuiceFS filesystem backed by object
+// storage. JuiceFS needs a metadata engine (e.g. Redis or SQLite) in
+// addition to the object store bucket.
+type JuiceFSBackupStorage struct {
+	Bucket string `json:"bucket"`
+	// MountPath is where the sidecar mounts the JuiceFS filesystem before
+	// running xtrabackup against it. Defaults to /var/lib/mysql-juicefs.
+	MountPath string `json:"mountPath,omitempty"`
+	// CredentialsSecretName is a secret holding the object storage
+	// credentials and the metadata engine URL (e.g. redis://... or
+	// sqlite3://...).
+	CredentialsSecretName string `json:"credentialsSecretName,omitempty"`
+}
+
+// ClusterCondition defines the observed state of a MysqlCluster at a point
+// in time.
+type ClusterCondition struct {
+	Type               ClusterConditionType  `json:"type"`
+	Status             apiv1.ConditionStatus `json:"status"`
+	LastTransitionTime metav1.Time           `json:"lastTransitionTime,omitempty"`
+	Reason             string                `json:"reason,omitempty"`
+	Message            string                `json:"message,omitempty"`
+}
+
+// ClusterConditionType defines the type of the cluster condition.
+type ClusterConditionType string
+
+const (
+	// ClusterConditionAvailable indicates whether the cluster can serve
+	// reads and writes.
+	ClusterConditionAvailable ClusterConditionType = "Available"
+	// ClusterConditionHealthy indicates whether all members of the cluster
+	// are replicating without errors.
+	ClusterConditionHealthy ClusterConditionType = "Healthy"
+	// ClusterConditionUpgradeSwitchover is set once a controlled master
+	// switchover for an in-progress upgrade has been triggered, so it's
+	// only triggered once per upgrade.
+	ClusterConditionUpgradeSwitchover ClusterConditionType = "UpgradeSwitchover"
+)
+
+// ClusterStatus defines the observed state of a MysqlCluster.
+type ClusterStatus struct {
+	// ReadyNodes represents the number of ready pods from the statefulset.
+	ReadyNodes int `json:"readyNodes,omitempty"`
+
+	// Conditions contains the list of the cluster conditions.
+	Conditions []ClusterCondition `json:"conditions,omitempty"`
+
+	// Nodes holds the per-pod status reported by the controller, including
+	// the MySQL version each pod is currently running. It's used to drive
+	// ordinal-aware rolling upgrades.
+	Nodes []NodeStatus `json:"nodes,omitempty"`
+
+	// MasterAddress is the hostname of the current master, as last
+	// observed by the cluster manager.
+	MasterAddress string `json:"masterAddress,omitempty"`
+
+	// ReplicaLagSeconds is the replication lag of the most delayed
+	// replica, as last observed by the cluster manager.
+	ReplicaLagSeconds int64 `json:"replicaLagSeconds,omitempty"`
+}
+
+// NodeStatus is the observed status of a single mysql pod.
+type NodeStatus struct {
+	// Name is the pod hostname, e.g. <cluster>-mysql-0.<cluster>-mysql.
+	Name string `json:"name"`
+	// Version is the MySQL version the pod was last observed running.
+	Version string `json:"version,omitempty"`
+}
+
+// PodSpec defines the spec for the mysql and sidecar pods.
+type PodSpec struct {
+	ImagePullPolicy apiv1.PullPolicy           `json:"imagePullPolicy,omitempty"`
+	Resources       apiv1.ResourceRequirements `json:"resources,omitempty"`
+
+	// ExtraEnv is merged into the env of the mysql and sidecar containers
+	// by AppendExtraEnv, letting operators inject things like vault-agent
+	// configuration or cloud-provider credentials without forking the
+	// operator.
+	ExtraEnv []apiv1.EnvVar `json:"extraEnv,omitempty"`
+	// ExtraEnvFrom is merged into the envFrom of the mysql and sidecar
+	// containers by AppendExtraEnvFrom.
+	ExtraEnvFrom []apiv1.EnvFromSource `json:"extraEnvFrom,omitempty"`
+	// ExtraVolumes is merged into the pod's volumes by AppendExtraVolumes,
+	// e.g. for a vault-agent sidecar or a custom TLS bundle.
+	ExtraVolumes []apiv1.Volume `json:"extraVolumes,omitempty"`
+	// ExtraVolumeMounts is merged into the volumeMounts of the mysql and
+	// sidecar containers by AppendExtraVolumeMounts.
+	ExtraVolumeMounts []apiv1.VolumeMount `json:"extraVolumeMounts,omitempty"`
+}
+
+// VolumeSpec defines the spec for the data volume used by mysql pods.
+type VolumeSpec struct {
+	AccessModes []apiv1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+	Resources   apiv1.ResourceRequirements         `json:"resources,omitempty"`
+}
@@ -0,0 +1,95 @@
+// Package clustering owns the long-running orchestrator polling that used
+// to happen synchronously, inline, on every MysqlCluster.GetMasterHost/
+// GetHealtySlaveHost call. A ClusterManager runs one goroutine per
+// MysqlCluster, keyed by its namespaced name, and serves lookups as cache
+// reads instead of orchestrator round-trips.
+package clustering
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	api "github.com/presslabs/titanium/pkg/apis/titanium/v1alpha1"
+)
+
+// defaultPollInterval is how often a cluster's worker refreshes its
+// orchestrator topology cache.
+const defaultPollInterval = 5 * time.Second
+
+// ClusterManager owns one polling worker per MysqlCluster.
+type ClusterManager struct {
+	mu       sync.RWMutex
+	clusters map[types.NamespacedName]*clusterWorker
+}
+
+// New returns an empty ClusterManager.
+func New() *ClusterManager {
+	return &ClusterManager{
+		clusters: make(map[types.NamespacedName]*clusterWorker),
+	}
+}
+
+// Update is meant to be called from the reconcile loop on every reconcile.
+// If a worker is already running for this cluster and nothing it cares
+// about has changed, it just swaps in the newer *api.MysqlCluster pointer
+// in place so the cached topology survives the call. The worker is only
+// stopped and recreated when a field its poll loop actually depends on
+// (e.g. MaxSlaveLatencySeconds) changed, since reconciles fire far more
+// often than the poll interval and restarting on every one of them would
+// reset the cache to empty on essentially every call.
+func (m *ClusterManager) Update(ctx context.Context, name types.NamespacedName, cluster *api.MysqlCluster) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if w, ok := m.clusters[name]; ok {
+		if w.configUnchanged(cluster) {
+			w.setCluster(cluster)
+			return
+		}
+		w.stop()
+	}
+
+	w := newClusterWorker(cluster)
+	m.clusters[name] = w
+	go w.run(ctx)
+}
+
+// Stop terminates the polling worker for the given cluster, e.g. when the
+// MysqlCluster is deleted.
+func (m *ClusterManager) Stop(name types.NamespacedName) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if w, ok := m.clusters[name]; ok {
+		w.stop()
+		delete(m.clusters, name)
+	}
+}
+
+// GetMasterHost returns the cached master hostname for the given cluster.
+// It returns the empty string if the cluster isn't tracked yet.
+func (m *ClusterManager) GetMasterHost(name types.NamespacedName) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if w, ok := m.clusters[name]; ok {
+		return w.masterHost()
+	}
+	return ""
+}
+
+// GetHealtySlaveHost returns the cached healthy-replica hostname for the
+// given cluster. It returns the empty string if the cluster isn't tracked
+// yet.
+func (m *ClusterManager) GetHealtySlaveHost(name types.NamespacedName) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if w, ok := m.clusters[name]; ok {
+		return w.healthySlaveHost()
+	}
+	return ""
+}
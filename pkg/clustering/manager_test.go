@@ -0,0 +1,64 @@
+package clustering
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestClusterManagerUpdatePreservesCacheWhenConfigUnchanged(t *testing.T) {
+	m := New()
+	ctx := context.Background()
+	name := types.NamespacedName{Namespace: "default", Name: "foo"}
+
+	m.Update(ctx, name, newTestCluster("foo", 5))
+
+	m.mu.RLock()
+	w := m.clusters[name]
+	m.mu.RUnlock()
+	w.mu.Lock()
+	w.master = "foo-mysql-0.foo-mysql"
+	w.mu.Unlock()
+
+	m.Update(ctx, name, newTestCluster("foo", 5))
+
+	m.mu.RLock()
+	got := m.clusters[name]
+	m.mu.RUnlock()
+
+	if got != w {
+		t.Error("expected Update to keep the same worker when config is unchanged")
+	}
+	if host := m.GetMasterHost(name); host != "foo-mysql-0.foo-mysql" {
+		t.Errorf("expected cached master to survive an unchanged-config Update, got %q", host)
+	}
+}
+
+func TestClusterManagerUpdateRestartsWorkerWhenConfigChanged(t *testing.T) {
+	m := New()
+	ctx := context.Background()
+	name := types.NamespacedName{Namespace: "default", Name: "foo"}
+
+	m.Update(ctx, name, newTestCluster("foo", 5))
+
+	m.mu.RLock()
+	w := m.clusters[name]
+	m.mu.RUnlock()
+	w.mu.Lock()
+	w.master = "foo-mysql-0.foo-mysql"
+	w.mu.Unlock()
+
+	m.Update(ctx, name, newTestCluster("foo", 10))
+
+	m.mu.RLock()
+	got := m.clusters[name]
+	m.mu.RUnlock()
+
+	if got == w {
+		t.Error("expected Update to recreate the worker when MaxSlaveLatencySeconds changed")
+	}
+	if host := m.GetMasterHost(name); host != "foo-mysql-0.foo-mysql" {
+		t.Errorf("expected a recreated worker's cache to reset to the default pod hostname, got %q", host)
+	}
+}
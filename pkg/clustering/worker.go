@@ -0,0 +1,166 @@
+package clustering
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	apiv1 "k8s.io/api/core/v1"
+
+	api "github.com/presslabs/titanium/pkg/apis/titanium/v1alpha1"
+	"github.com/presslabs/titanium/pkg/metrics"
+	orc "github.com/presslabs/titanium/pkg/util/orchestrator"
+)
+
+// clusterWorker polls orchestrator for a single MysqlCluster's topology on
+// an interval and caches the result.
+type clusterWorker struct {
+	stopC chan struct{}
+
+	mu           sync.RWMutex
+	cluster      *api.MysqlCluster
+	master       string
+	healthySlave string
+}
+
+func newClusterWorker(cluster *api.MysqlCluster) *clusterWorker {
+	return &clusterWorker{
+		cluster: cluster,
+		stopC:   make(chan struct{}),
+		master:  cluster.GetPodHostName(0),
+	}
+}
+
+func (w *clusterWorker) stop() {
+	close(w.stopC)
+}
+
+// getCluster returns the most recently handed-in *api.MysqlCluster, so a
+// concurrent Update()'s setCluster doesn't race with poll() reading it.
+func (w *clusterWorker) getCluster() *api.MysqlCluster {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.cluster
+}
+
+// setCluster swaps in a newer *api.MysqlCluster without resetting the
+// cached topology, for reconciles that don't change anything poll()
+// depends on.
+func (w *clusterWorker) setCluster(cluster *api.MysqlCluster) {
+	w.mu.Lock()
+	w.cluster = cluster
+	w.mu.Unlock()
+}
+
+// configUnchanged reports whether cluster matches the one this worker was
+// created or last updated with in everything that matters to poll()
+// (right now, just the replica-lag threshold it polls against).
+func (w *clusterWorker) configUnchanged(cluster *api.MysqlCluster) bool {
+	cur := w.getCluster()
+	return cur.Spec.MaxSlaveLatencySeconds == cluster.Spec.MaxSlaveLatencySeconds
+}
+
+func (w *clusterWorker) run(ctx context.Context) {
+	w.poll()
+
+	ticker := time.NewTicker(defaultPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stopC:
+			return
+		case <-ticker.C:
+			w.poll()
+		}
+	}
+}
+
+// poll fetches the current topology from orchestrator, updates the cache,
+// and records the observed state onto MysqlCluster.Status.
+func (w *clusterWorker) poll() {
+	cluster := w.getCluster()
+
+	uri := cluster.Spec.GetOrcUri()
+	if len(uri) == 0 {
+		return
+	}
+
+	client := orc.NewFromUri(uri)
+
+	cluster.MaybeSwitchoverForUpgrade(client)
+
+	master := cluster.GetPodHostName(0)
+	available := false
+	if inst, err := client.Master(cluster.Name); err == nil {
+		master = inst.Key.Hostname
+		available = true
+		cluster.RecordObservedVersion(inst.Key.Hostname, inst.Version)
+	} else {
+		glog.Warningf("[clustering] failed to fetch master for %s: %s", cluster.Name, err)
+	}
+
+	healthySlave := master
+	healthy := available
+	var lag int64
+	if replicas, err := client.ClusterOSCReplicas(cluster.Name); err == nil {
+		for _, r := range replicas {
+			cluster.RecordObservedVersion(r.Key.Hostname, r.Version)
+			if !r.SecondsBehindMaster.Valid {
+				healthy = false
+				continue
+			}
+			if r.SecondsBehindMaster.Int64 > lag {
+				lag = r.SecondsBehindMaster.Int64
+			}
+			if r.SecondsBehindMaster.Int64 <= int64(cluster.Spec.MaxSlaveLatencySeconds) {
+				healthySlave = r.Key.Hostname
+			}
+		}
+	} else {
+		glog.Warningf("[clustering] failed to fetch replicas for %s: %s", cluster.Name, err)
+		healthy = false
+	}
+
+	w.mu.Lock()
+	previousMaster := w.master
+	w.master = master
+	w.healthySlave = healthySlave
+	w.mu.Unlock()
+
+	cluster.Status.MasterAddress = master
+	cluster.Status.ReplicaLagSeconds = lag
+	cluster.SetClusterCondition(api.ClusterConditionAvailable, conditionStatus(available), "OrchestratorPoll", "")
+	cluster.SetClusterCondition(api.ClusterConditionHealthy, conditionStatus(healthy), "OrchestratorPoll", "")
+
+	namespace, name := cluster.Namespace, cluster.Name
+	metrics.SetAvailable(namespace, name, available)
+	metrics.SetHealthy(namespace, name, healthy)
+	metrics.SetReadyNodes(namespace, name, cluster.Status.ReadyNodes)
+	metrics.SetReplicaLagSeconds(namespace, name, lag)
+	if previousMaster != "" && master != previousMaster {
+		metrics.IncMasterSwitch(namespace, name)
+	}
+}
+
+func (w *clusterWorker) masterHost() string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.master
+}
+
+func (w *clusterWorker) healthySlaveHost() string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.healthySlave
+}
+
+func conditionStatus(ok bool) apiv1.ConditionStatus {
+	if ok {
+		return apiv1.ConditionTrue
+	}
+	return apiv1.ConditionFalse
+}
@@ -0,0 +1,50 @@
+package clustering
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/presslabs/titanium/pkg/apis/titanium/v1alpha1"
+)
+
+func newTestCluster(name string, maxSlaveLatencySeconds int32) *api.MysqlCluster {
+	return &api.MysqlCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: api.ClusterSpec{
+			MaxSlaveLatencySeconds: maxSlaveLatencySeconds,
+		},
+	}
+}
+
+func TestClusterWorkerConfigUnchanged(t *testing.T) {
+	w := newClusterWorker(newTestCluster("foo", 5))
+
+	if !w.configUnchanged(newTestCluster("foo", 5)) {
+		t.Error("expected configUnchanged to report true when MaxSlaveLatencySeconds is the same")
+	}
+	if w.configUnchanged(newTestCluster("foo", 10)) {
+		t.Error("expected configUnchanged to report false when MaxSlaveLatencySeconds changed")
+	}
+}
+
+func TestClusterWorkerSetClusterPreservesCache(t *testing.T) {
+	w := newClusterWorker(newTestCluster("foo", 5))
+
+	w.mu.Lock()
+	w.master = "foo-mysql-0.foo-mysql"
+	w.healthySlave = "foo-mysql-1.foo-mysql"
+	w.mu.Unlock()
+
+	w.setCluster(newTestCluster("foo", 5))
+
+	if got := w.masterHost(); got != "foo-mysql-0.foo-mysql" {
+		t.Errorf("expected setCluster to leave the cached master alone, got %q", got)
+	}
+	if got := w.healthySlaveHost(); got != "foo-mysql-1.foo-mysql" {
+		t.Errorf("expected setCluster to leave the cached healthy slave alone, got %q", got)
+	}
+	if got := w.getCluster().Spec.MaxSlaveLatencySeconds; got != 5 {
+		t.Errorf("expected setCluster to swap in the new cluster pointer, got MaxSlaveLatencySeconds=%d", got)
+	}
+}
@@ -0,0 +1,108 @@
+// Package metrics exposes per-cluster Prometheus series derived from
+// orchestrator's transient topology view, so cluster-level availability
+// and health survive as durable observability without requiring the
+// separate mysqld-exporter for this.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	clusterAvailable = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mysql_cluster_available",
+		Help: "Whether orchestrator reports a reachable master for the cluster (1) or not (0).",
+	}, []string{"namespace", "name"})
+
+	clusterHealthy = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mysql_cluster_healthy",
+		Help: "Whether all replicas are replicating without errors (1) or not (0).",
+	}, []string{"namespace", "name"})
+
+	clusterReadyNodes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mysql_cluster_ready_nodes",
+		Help: "Number of ready pods in the cluster's statefulset.",
+	}, []string{"namespace", "name"})
+
+	clusterReplicaLagSeconds = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mysql_cluster_replica_lag_seconds",
+		Help: "Replication lag, in seconds, of the most delayed replica.",
+	}, []string{"namespace", "name"})
+
+	clusterMasterSwitchTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "mysql_cluster_master_switch_total",
+		Help: "Number of times mastership has moved to a different pod.",
+	}, []string{"namespace", "name"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		clusterAvailable,
+		clusterHealthy,
+		clusterReadyNodes,
+		clusterReplicaLagSeconds,
+		clusterMasterSwitchTotal,
+	)
+}
+
+// Register creates the series for a cluster, defaulted to zero, so it
+// shows up in scrapes as soon as the cluster exists rather than only
+// after the first poll.
+func Register(namespace, name string) {
+	clusterAvailable.WithLabelValues(namespace, name)
+	clusterHealthy.WithLabelValues(namespace, name)
+	clusterReadyNodes.WithLabelValues(namespace, name)
+	clusterReplicaLagSeconds.WithLabelValues(namespace, name)
+	clusterMasterSwitchTotal.WithLabelValues(namespace, name)
+}
+
+// Unregister removes the series for a cluster so stale series don't
+// linger in scrapes after the cluster is deleted.
+func Unregister(namespace, name string) {
+	clusterAvailable.DeleteLabelValues(namespace, name)
+	clusterHealthy.DeleteLabelValues(namespace, name)
+	clusterReadyNodes.DeleteLabelValues(namespace, name)
+	clusterReplicaLagSeconds.DeleteLabelValues(namespace, name)
+	clusterMasterSwitchTotal.DeleteLabelValues(namespace, name)
+}
+
+// SetAvailable records whether orchestrator reports a reachable master.
+func SetAvailable(namespace, name string, available bool) {
+	clusterAvailable.WithLabelValues(namespace, name).Set(boolToFloat(available))
+}
+
+// SetHealthy records whether the cluster is replicating within bounds.
+func SetHealthy(namespace, name string, healthy bool) {
+	clusterHealthy.WithLabelValues(namespace, name).Set(boolToFloat(healthy))
+}
+
+// SetReadyNodes records the number of ready pods in the cluster.
+func SetReadyNodes(namespace, name string, readyNodes int) {
+	clusterReadyNodes.WithLabelValues(namespace, name).Set(float64(readyNodes))
+}
+
+// SetReplicaLagSeconds records the lag of the most delayed replica.
+func SetReplicaLagSeconds(namespace, name string, seconds int64) {
+	clusterReplicaLagSeconds.WithLabelValues(namespace, name).Set(float64(seconds))
+}
+
+// IncMasterSwitch records that mastership moved to a different pod.
+func IncMasterSwitch(namespace, name string) {
+	clusterMasterSwitchTotal.WithLabelValues(namespace, name).Inc()
+}
+
+// Handler returns the http.Handler the controller-manager binary mounts at
+// /metrics to make these series scrapeable.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+func boolToFloat(v bool) float64 {
+	if v {
+		return 1
+	}
+	return 0
+}